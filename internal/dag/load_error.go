@@ -0,0 +1,29 @@
+package dag
+
+import "fmt"
+
+// LoadError reports why a DAG YAML file failed to load, with enough
+// position information that a caller can point a user at the offending
+// line instead of just printing the underlying error.
+type LoadError struct {
+	// Filename is the path of the DAG file that failed to load.
+	Filename string
+	// Line and Column are the 1-indexed position of the parse failure,
+	// or zero if the loader could not attribute one.
+	Line, Column int
+	// Snippet is the offending line's text, or empty if unavailable.
+	Snippet string
+	// Cause is the underlying error.
+	Cause error
+}
+
+func (e *LoadError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", e.Filename, e.Line, e.Cause)
+	}
+	return fmt.Sprintf("%s: %s", e.Filename, e.Cause)
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Cause
+}
@@ -0,0 +1,130 @@
+package elector
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const defaultLockKey = "dagu/scheduler/leader"
+
+// ConsulConfig configures a Consul-backed Elector.
+type ConsulConfig struct {
+	// Address is the Consul HTTP API address (e.g. "127.0.0.1:8500"). If
+	// empty, the default from the Consul API client is used.
+	Address string
+	// Key is the KV path used for the leader lock.
+	Key string
+	// TTL is the session TTL. The elector renews the session well before
+	// it expires so a follower can take over quickly if the leader dies.
+	TTL time.Duration
+}
+
+// ConsulElector implements Elector on top of a Consul session-bound KV
+// lock, the same primitive Prometheus uses for Consul-based service
+// discovery.
+type ConsulElector struct {
+	cfg      ConsulConfig
+	lock     *consulapi.Lock
+	leader   int32
+	changes  chan bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewConsulElector creates a ConsulElector. It does not attempt to
+// acquire the lock until Start is called.
+func NewConsulElector(cfg ConsulConfig) (*ConsulElector, error) {
+	if cfg.Key == "" {
+		cfg.Key = defaultLockKey
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Second
+	}
+	ccfg := consulapi.DefaultConfig()
+	if cfg.Address != "" {
+		ccfg.Address = cfg.Address
+	}
+	client, err := consulapi.NewClient(ccfg)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := client.LockOpts(&consulapi.LockOptions{
+		Key:         cfg.Key,
+		SessionTTL:  cfg.TTL.String(),
+		SessionName: "dagu-scheduler",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ConsulElector{
+		cfg:     cfg,
+		lock:    lock,
+		changes: make(chan bool, 1),
+		stopCh:  make(chan struct{}),
+	}, nil
+}
+
+func (e *ConsulElector) IsLeader() bool       { return atomic.LoadInt32(&e.leader) == 1 }
+func (e *ConsulElector) Changes() <-chan bool { return e.changes }
+
+func (e *ConsulElector) Start() error {
+	go e.run()
+	return nil
+}
+
+// Stop is safe to call more than once; only the first call releases the
+// lock and stops run's session loop.
+func (e *ConsulElector) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		_ = e.lock.Unlock()
+	})
+}
+
+func (e *ConsulElector) run() {
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		default:
+		}
+		lostCh, err := e.lock.Lock(e.stopCh)
+		if err != nil {
+			log.Printf("elector: failed to acquire consul lock: %s", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		if lostCh == nil {
+			// Stop was called while waiting for the lock.
+			return
+		}
+		e.setLeader(true)
+		select {
+		case <-lostCh:
+			e.setLeader(false)
+		case <-e.stopCh:
+			e.setLeader(false)
+			return
+		}
+	}
+}
+
+func (e *ConsulElector) setLeader(v bool) {
+	var n int32
+	if v {
+		n = 1
+	}
+	if atomic.SwapInt32(&e.leader, n) == n {
+		return
+	}
+	select {
+	case e.changes <- v:
+	default:
+	}
+}
+
+var _ Elector = (*ConsulElector)(nil)
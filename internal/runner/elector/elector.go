@@ -0,0 +1,40 @@
+// Package elector provides leader election for the dagu scheduler so that
+// several replicas can be run for redundancy without every replica firing
+// the same schedule.
+package elector
+
+// Elector decides whether the local process is allowed to act as the
+// active scheduler. Implementations notify callers of leadership changes
+// over the channel returned by Changes.
+type Elector interface {
+	// IsLeader reports whether this process currently holds leadership.
+	IsLeader() bool
+	// Changes returns a channel that receives the new leadership state
+	// whenever it changes.
+	Changes() <-chan bool
+	// Start begins participating in the election. IsLeader does not
+	// reflect a meaningful value until Start has been called.
+	Start() error
+	// Stop releases any held lock and stops participating in the
+	// election.
+	Stop()
+}
+
+// NoOpElector always reports leadership, for single-node setups that do
+// not need coordination across replicas.
+type NoOpElector struct {
+	changes chan bool
+}
+
+// NewNoOpElector creates an Elector that always considers the local
+// process the leader.
+func NewNoOpElector() *NoOpElector {
+	return &NoOpElector{changes: make(chan bool)}
+}
+
+func (e *NoOpElector) IsLeader() bool       { return true }
+func (e *NoOpElector) Changes() <-chan bool { return e.changes }
+func (e *NoOpElector) Start() error         { return nil }
+func (e *NoOpElector) Stop()                {}
+
+var _ Elector = (*NoOpElector)(nil)
@@ -0,0 +1,75 @@
+package elector
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNoOpElectorAlwaysLeader(t *testing.T) {
+	e := NewNoOpElector()
+	if err := e.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer e.Stop()
+	if !e.IsLeader() {
+		t.Fatal("NoOpElector.IsLeader() = false, want true")
+	}
+	select {
+	case v := <-e.Changes():
+		t.Fatalf("unexpected leadership change: %v", v)
+	default:
+	}
+}
+
+func TestFileElectorHandsOffOnStop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	cfg := FileConfig{Path: path, PollInterval: 10 * time.Millisecond}
+	a := NewFileElector(cfg)
+	b := NewFileElector(cfg)
+
+	if err := a.Start(); err != nil {
+		t.Fatalf("a.Start: %v", err)
+	}
+	waitLeader(t, a, true)
+
+	if err := b.Start(); err != nil {
+		t.Fatalf("b.Start: %v", err)
+	}
+	defer b.Stop()
+
+	if b.IsLeader() {
+		t.Fatal("b became leader while a still holds the lock")
+	}
+
+	a.Stop()
+	waitLeader(t, b, true)
+}
+
+func TestFileElectorStopIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader.lock")
+	e := NewFileElector(FileConfig{Path: path, PollInterval: 10 * time.Millisecond})
+	if err := e.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitLeader(t, e, true)
+	e.Stop()
+	e.Stop()
+}
+
+// waitLeader waits for e.Changes() to report want, failing the test if it
+// doesn't happen within a generous deadline.
+func waitLeader(t *testing.T, e Elector, want bool) {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case v := <-e.Changes():
+			if v == want {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for leadership = %v", want)
+		}
+	}
+}
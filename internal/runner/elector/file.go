@@ -0,0 +1,96 @@
+package elector
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// FileConfig configures a FileElector.
+type FileConfig struct {
+	// Path is the lock file used to coordinate leadership. It must live
+	// on a filesystem shared by all replicas (e.g. NFS).
+	Path string
+	// PollInterval controls how often a follower retries to acquire the
+	// lock.
+	PollInterval time.Duration
+}
+
+// FileElector implements Elector with a simple advisory file lock, for
+// users who do not run Consul. The OS releases the lock when the leader
+// process exits or dies, letting a follower take over on the next poll.
+type FileElector struct {
+	cfg      FileConfig
+	flock    *flock.Flock
+	leader   int32
+	changes  chan bool
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewFileElector creates a FileElector. It does not attempt to acquire
+// the lock until Start is called.
+func NewFileElector(cfg FileConfig) *FileElector {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	return &FileElector{
+		cfg:     cfg,
+		flock:   flock.New(cfg.Path),
+		changes: make(chan bool, 1),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+func (e *FileElector) IsLeader() bool       { return atomic.LoadInt32(&e.leader) == 1 }
+func (e *FileElector) Changes() <-chan bool { return e.changes }
+
+func (e *FileElector) Start() error {
+	go e.run()
+	return nil
+}
+
+// Stop is safe to call more than once; only the first call releases the
+// lock and stops run's poll loop.
+func (e *FileElector) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+		_ = e.flock.Unlock()
+		e.setLeader(false)
+	})
+}
+
+func (e *FileElector) run() {
+	ticker := time.NewTicker(e.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if !e.IsLeader() {
+			if ok, err := e.flock.TryLock(); err == nil && ok {
+				e.setLeader(true)
+			}
+		}
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *FileElector) setLeader(v bool) {
+	var n int32
+	if v {
+		n = 1
+	}
+	if atomic.SwapInt32(&e.leader, n) == n {
+		return
+	}
+	select {
+	case e.changes <- v:
+	default:
+	}
+}
+
+var _ Elector = (*FileElector)(nil)
@@ -1,21 +1,20 @@
 package runner
 
 import (
+	"context"
+	"errors"
 	"log"
-	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/yohamta/dagu/internal/admin"
 	"github.com/yohamta/dagu/internal/dag"
-	"github.com/yohamta/dagu/internal/runner/filenotify"
+	"github.com/yohamta/dagu/internal/runner/elector"
+	"github.com/yohamta/dagu/internal/runner/source"
 	"github.com/yohamta/dagu/internal/settings"
 	"github.com/yohamta/dagu/internal/storage"
 	"github.com/yohamta/dagu/internal/suspend"
-	"github.com/yohamta/dagu/internal/utils"
 )
 
 type EntryType int
@@ -55,6 +54,19 @@ type EntryReader interface {
 }
 
 func newEntryReader(cfg *admin.Config) *entryReader {
+	el, err := newElector(cfg)
+	if err != nil {
+		log.Printf("failed to init leader elector, falling back to single-node mode: %v", err)
+		el = elector.NewNoOpElector()
+	}
+	if err := el.Start(); err != nil {
+		log.Printf("failed to start leader elector: %v", err)
+	}
+	src, err := newSource(cfg)
+	if err != nil {
+		log.Fatalf("failed to init dag source: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	er := &entryReader{
 		Admin: cfg,
 		suspendChecker: suspend.NewSuspendChecker(
@@ -62,26 +74,101 @@ func newEntryReader(cfg *admin.Config) *entryReader {
 				settings.MustGet(settings.SETTING__SUSPEND_FLAGS_DIR),
 			),
 		),
-		dagsLock: sync.Mutex{},
-		dags:     map[string]*dag.DAG{},
+		elector: el,
+		source:  src,
+		ctx:     ctx,
+		cancel:  cancel,
+		dags:    map[string]*dag.DAG{},
+		errors:  map[string]*dag.LoadError{},
 	}
 	if err := er.initDags(); err != nil {
 		log.Printf("failed to init entry dags %v", err)
 	}
 	go er.watchDags()
+	go er.watchLeadership()
 	return er
 }
 
+// newSource builds the DAGSource configured for this scheduler instance.
+// cfg.Sources takes precedence; when it is empty, cfg.DAGs is sugar for a
+// single filesystem source, matching the pre-existing behavior.
+func newSource(cfg *admin.Config) (source.DAGSource, error) {
+	if len(cfg.Sources) == 0 {
+		return source.New(source.Config{
+			Type: source.TypeFilesystem,
+			Filesystem: source.FilesystemConfig{
+				Dir:          cfg.DAGs,
+				Identity:     cfg.DAGIdentity.Strategy,
+				IncludeGlobs: cfg.DAGIncludeGlobs,
+				ExcludeGlobs: cfg.DAGExcludeGlobs,
+			},
+		})
+	}
+	return source.NewMulti(cfg.Sources)
+}
+
+// newElector builds the Elector configured for this scheduler instance.
+// It defaults to NoOpElector when leader election is left unconfigured so
+// single-node setups behave exactly as before.
+func newElector(cfg *admin.Config) (elector.Elector, error) {
+	switch cfg.Scheduler.LeaderElection.Backend {
+	case admin.LeaderElectionBackendConsul:
+		return elector.NewConsulElector(elector.ConsulConfig{
+			Address: cfg.Scheduler.LeaderElection.Consul.Address,
+			Key:     cfg.Scheduler.LeaderElection.Consul.Key,
+			TTL:     cfg.Scheduler.LeaderElection.TTL,
+		})
+	case admin.LeaderElectionBackendFile:
+		return elector.NewFileElector(elector.FileConfig{
+			Path:         cfg.Scheduler.LeaderElection.File.Path,
+			PollInterval: cfg.Scheduler.LeaderElection.TTL,
+		}), nil
+	default:
+		return elector.NewNoOpElector(), nil
+	}
+}
+
 type entryReader struct {
 	Admin          *admin.Config
 	suspendChecker *suspend.SuspendChecker
+	elector        elector.Elector
+	source         source.DAGSource
+	ctx            context.Context
+	cancel         context.CancelFunc
 	dagsLock       sync.Mutex
-	dags           map[string]*dag.DAG
+	// dags is keyed by the DAGRef.Name that source resolved each DAG to.
+	// For the filesystem source that is a stable identity rather than
+	// the bare file name, so a rename of the underlying file is treated
+	// as an update rather than a delete+create.
+	dags map[string]*dag.DAG
+	// errors holds the most recent load failure per DAGRef.Name, so the
+	// admin UI can render why a DAG is missing from the schedule instead
+	// of it silently disappearing. A successful (re)load clears the
+	// entry.
+	errors map[string]*dag.LoadError
 }
 
 var _ EntryReader = (*entryReader)(nil)
 
+// Close stops this entryReader: it cancels the context passed to
+// watchDags and watchLeadership, which closes the source's event channel
+// and lets both goroutines return, then releases the elector's
+// lock so another replica can take over leadership without waiting out
+// a lease timeout.
+func (er *entryReader) Close() {
+	er.cancel()
+	er.elector.Stop()
+}
+
+// Read returns the scheduled entries. Followers always return an empty
+// slice so Entry.Invoke never runs anywhere but on the elected leader,
+// while watchDags keeps the in-memory dags map warm on every replica so
+// failover is instant.
 func (er *entryReader) Read(now time.Time) ([]*Entry, error) {
+	if !er.elector.IsLeader() {
+		return []*Entry{}, nil
+	}
+
 	entries := []*Entry{}
 	er.dagsLock.Lock()
 	defer er.dagsLock.Unlock()
@@ -102,6 +189,11 @@ func (er *entryReader) Read(now time.Time) ([]*Entry, error) {
 	}
 
 	for _, d := range er.dags {
+		// suspendChecker keys on d's filename, not the key er.dags is
+		// actually keyed by: a path relative to Admin.DAGs for recursive
+		// subdirectories, or an opaque id under identity.StrategyInodeDevice
+		// /StrategyMarker. Either way a DAG moved or renamed loses its
+		// suspend flag.
 		if er.suspendChecker.IsSuspended(d) {
 			continue
 		}
@@ -113,70 +205,109 @@ func (er *entryReader) Read(now time.Time) ([]*Entry, error) {
 	return entries, nil
 }
 
-func (er *entryReader) initDags() error {
+// Errors returns the most recent load failure for each DAG that failed
+// to load, keyed by the same name used in Read's schedule. The admin
+// HTTP API surfaces this so the web UI can render the offending file and
+// line instead of the DAG just vanishing from the schedule.
+func (er *entryReader) Errors() map[string]*dag.LoadError {
 	er.dagsLock.Lock()
 	defer er.dagsLock.Unlock()
-	cl := dag.Loader{}
-	fis, err := os.ReadDir(er.Admin.DAGs)
+	errs := make(map[string]*dag.LoadError, len(er.errors))
+	for name, e := range er.errors {
+		errs[name] = e
+	}
+	return errs
+}
+
+func (er *entryReader) initDags() error {
+	refs, err := er.source.List()
 	if err != nil {
 		return err
 	}
-	fileNames := []string{}
-	for _, fi := range fis {
-		if utils.MatchExtension(fi.Name(), dag.EXTENSIONS) {
-			dag, err := cl.LoadHeadOnly(filepath.Join(er.Admin.DAGs, fi.Name()))
-			if err != nil {
-				log.Printf("init dags failed to read dag config: %s", err)
-				continue
-			}
-			er.dags[fi.Name()] = dag
-			fileNames = append(fileNames, fi.Name())
+	er.dagsLock.Lock()
+	defer er.dagsLock.Unlock()
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		d, err := er.source.Load(ref)
+		if err != nil {
+			le := asLoadError(ref, err)
+			er.errors[ref.Name] = le
+			log.Printf("init dags failed to read dag config %s:%d: %s", le.Filename, le.Line, le.Cause)
+			continue
 		}
+		delete(er.errors, ref.Name)
+		er.dags[ref.Name] = d
+		names = append(names, ref.DisplayName)
 	}
-	log.Printf("init scheduler dags: %s", strings.Join(fileNames, ","))
+	log.Printf("init scheduler dags: %s", strings.Join(names, ","))
 	return nil
 }
 
+// asLoadError normalizes err into a *dag.LoadError so callers always have
+// a Filename to log, even when dag.Loader could not attribute the
+// failure to a specific line (e.g. the file could not be opened at all).
+func asLoadError(ref source.DAGRef, err error) *dag.LoadError {
+	var le *dag.LoadError
+	if errors.As(err, &le) {
+		return le
+	}
+	return &dag.LoadError{Filename: ref.Path, Cause: err}
+}
+
+// watchDags applies the source's change events to er.dags until the
+// event channel closes, which happens when er.ctx is canceled.
 func (er *entryReader) watchDags() {
-	cl := dag.Loader{}
-	watcher, err := filenotify.New(time.Minute)
+	events, err := er.source.Watch(er.ctx)
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("failed to watch dag source: %s", err)
+		return
 	}
-	defer func() {
-		_ = watcher.Close()
-	}()
-	_ = watcher.Add(er.Admin.DAGs)
-	for {
-		select {
-		case event, ok := <-watcher.Events():
-			if !ok {
-				return
-			}
-			if !utils.MatchExtension(event.Name, dag.EXTENSIONS) {
+	for ev := range events {
+		switch ev.Type {
+		case source.EventCreate, source.EventUpdate:
+			d, err := er.source.Load(ev.Ref)
+			if err != nil {
+				le := asLoadError(ev.Ref, err)
+				er.dagsLock.Lock()
+				er.errors[ev.Ref.Name] = le
+				er.dagsLock.Unlock()
+				log.Printf("failed to read dag config %s:%d: %s", le.Filename, le.Line, le.Cause)
 				continue
 			}
 			er.dagsLock.Lock()
-			if event.Op == fsnotify.Create || event.Op == fsnotify.Write {
-				dag, err := cl.LoadHeadOnly(filepath.Join(er.Admin.DAGs, filepath.Base(event.Name)))
-				if err != nil {
-					log.Printf("failed to read dag config: %s", err)
-				} else {
-					er.dags[filepath.Base(event.Name)] = dag
-					log.Printf("reload dag entry %s", event.Name)
-				}
-			}
-			if event.Op == fsnotify.Rename || event.Op == fsnotify.Remove {
-				delete(er.dags, filepath.Base(event.Name))
-				log.Printf("remove dag entry %s", event.Name)
-			}
+			delete(er.errors, ev.Ref.Name)
+			er.dags[ev.Ref.Name] = d
+			er.dagsLock.Unlock()
+			log.Printf("reload dag entry %s", ev.Ref.DisplayName)
+		case source.EventRemove:
+			er.dagsLock.Lock()
+			delete(er.dags, ev.Ref.Name)
+			delete(er.errors, ev.Ref.Name)
 			er.dagsLock.Unlock()
-		case err, ok := <-watcher.Errors():
+			log.Printf("remove dag entry %s", ev.Ref.DisplayName)
+		}
+	}
+}
+
+// watchLeadership logs leadership transitions as they happen, so a
+// replica's logs show when it started or stopped acting as the active
+// scheduler instead of that only being inferable from Read's behavior.
+// It returns once er.ctx is canceled, which Close does, or if
+// er.elector.Changes() is closed.
+func (er *entryReader) watchLeadership() {
+	for {
+		select {
+		case <-er.ctx.Done():
+			return
+		case leader, ok := <-er.elector.Changes():
 			if !ok {
 				return
 			}
-			log.Println("watch entry dags error:", err)
+			if leader {
+				log.Printf("became leader")
+			} else {
+				log.Printf("lost leadership")
+			}
 		}
 	}
-
 }
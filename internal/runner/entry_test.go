@@ -0,0 +1,100 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yohamta/dagu/internal/dag"
+	"github.com/yohamta/dagu/internal/runner/source"
+)
+
+// fakeDAGSource is a source.DAGSource whose List snapshot is fixed and
+// whose Load result per ref.Name is controlled directly by the test.
+type fakeDAGSource struct {
+	listRefs []source.DAGRef
+	loadErr  map[string]error // ref.Name -> error; absent means success
+}
+
+func (f *fakeDAGSource) List() ([]source.DAGRef, error) { return f.listRefs, nil }
+
+func (f *fakeDAGSource) Load(ref source.DAGRef) (*dag.DAG, error) {
+	if err, ok := f.loadErr[ref.Name]; ok {
+		return nil, err
+	}
+	return &dag.DAG{}, nil
+}
+
+func (f *fakeDAGSource) Watch(ctx context.Context) (<-chan source.SourceEvent, error) {
+	return nil, nil
+}
+
+var _ source.DAGSource = (*fakeDAGSource)(nil)
+
+func TestAsLoadErrorFallsBackToFilenameAndCause(t *testing.T) {
+	cause := errors.New("boom")
+	ref := source.DAGRef{Path: "/dags/a.yaml"}
+
+	le := asLoadError(ref, cause)
+
+	if le.Filename != ref.Path || le.Cause != cause || le.Line != 0 {
+		t.Fatalf("got %+v, want Filename=%s Cause=%v Line=0", le, ref.Path, cause)
+	}
+}
+
+func TestAsLoadErrorUnwrapsExistingLoadError(t *testing.T) {
+	inner := &dag.LoadError{Filename: "a.yaml", Line: 4, Cause: errors.New("bad")}
+	wrapped := fmt.Errorf("load failed: %w", inner)
+	ref := source.DAGRef{Path: "/dags/a.yaml"}
+
+	le := asLoadError(ref, wrapped)
+
+	if le != inner {
+		t.Fatalf("got %+v, want the original *dag.LoadError unwrapped, unchanged", le)
+	}
+}
+
+func TestInitDagsClearsErrorOnSuccessfulReload(t *testing.T) {
+	ref := source.DAGRef{Name: "a", Path: "/dags/a.yaml"}
+	src := &fakeDAGSource{
+		listRefs: []source.DAGRef{ref},
+		loadErr:  map[string]error{"a": errors.New("bad yaml")},
+	}
+	er := &entryReader{source: src, dags: map[string]*dag.DAG{}, errors: map[string]*dag.LoadError{}}
+
+	if err := er.initDags(); err != nil {
+		t.Fatalf("initDags: %v", err)
+	}
+	if _, ok := er.Errors()["a"]; !ok {
+		t.Fatal("expected an error recorded for a failed load")
+	}
+	if _, ok := er.dags["a"]; ok {
+		t.Fatal("a dag that failed to load should not appear in dags")
+	}
+
+	src.loadErr = map[string]error{}
+	if err := er.initDags(); err != nil {
+		t.Fatalf("initDags: %v", err)
+	}
+	if _, ok := er.Errors()["a"]; ok {
+		t.Fatal("a successful reload should have cleared the earlier error")
+	}
+	if _, ok := er.dags["a"]; !ok {
+		t.Fatal("a successfully reloaded dag should appear in dags")
+	}
+}
+
+func TestErrorsReturnsACopy(t *testing.T) {
+	er := &entryReader{
+		dags:   map[string]*dag.DAG{},
+		errors: map[string]*dag.LoadError{"a": {Filename: "a.yaml"}},
+	}
+
+	errs := er.Errors()
+	errs["b"] = &dag.LoadError{Filename: "b.yaml"}
+
+	if _, ok := er.errors["b"]; ok {
+		t.Fatal("mutating the map returned by Errors() leaked into entryReader.errors")
+	}
+}
@@ -0,0 +1,42 @@
+// Package identity resolves a stable identity for a DAG file so that
+// renaming the underlying file is not mistaken for a delete followed by a
+// create, which would otherwise lose schedule history, suspend flags and
+// running state tied to the old name.
+package identity
+
+// Strategy selects how a DAG file's identity is computed.
+type Strategy string
+
+const (
+	// StrategyPath keeps today's behavior: the identity is the file's
+	// base name, so renames are treated as a new DAG.
+	StrategyPath Strategy = "path"
+	// StrategyInodeDevice identifies a file by its inode and device
+	// number, which survive a rename on the same filesystem.
+	StrategyInodeDevice Strategy = "inode_device"
+	// StrategyMarker identifies a file by a `# dagu-id: <uuid>` header
+	// written into the first line of the YAML, generating one on first
+	// sight if it is missing.
+	StrategyMarker Strategy = "marker"
+)
+
+// Resolver computes a stable identity string for a DAG file.
+type Resolver interface {
+	// Resolve returns the identity of the file at path.
+	Resolve(path string) (string, error)
+	// Strategy reports which Strategy this resolver implements.
+	Strategy() Strategy
+}
+
+// New returns the Resolver for the given strategy. An empty strategy
+// defaults to StrategyPath, matching the pre-existing behavior.
+func New(s Strategy) Resolver {
+	switch s {
+	case StrategyInodeDevice:
+		return inodeResolver{}
+	case StrategyMarker:
+		return markerResolver{}
+	default:
+		return pathResolver{}
+	}
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package identity
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// inodeResolver implements StrategyInodeDevice on Unix using the device
+// and inode numbers reported by stat(2), which are stable across a
+// rename on the same filesystem.
+type inodeResolver struct{}
+
+func (inodeResolver) Resolve(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", fmt.Errorf("identity: inode_device strategy is not supported on this platform")
+	}
+	return fmt.Sprintf("%d:%d", st.Dev, st.Ino), nil
+}
+
+func (inodeResolver) Strategy() Strategy { return StrategyInodeDevice }
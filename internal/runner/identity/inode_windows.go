@@ -0,0 +1,32 @@
+//go:build windows
+
+package identity
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// inodeResolver implements StrategyInodeDevice on Windows using the
+// volume serial number and file index reported by
+// GetFileInformationByHandle, the closest equivalent to a Unix inode.
+type inodeResolver struct{}
+
+func (inodeResolver) Resolve(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var info windows.ByHandleFileInformation
+	if err := windows.GetFileInformationByHandle(windows.Handle(f.Fd()), &info); err != nil {
+		return "", err
+	}
+	fileIndex := uint64(info.FileIndexHigh)<<32 | uint64(info.FileIndexLow)
+	return fmt.Sprintf("%d:%d", info.VolumeSerialNumber, fileIndex), nil
+}
+
+func (inodeResolver) Strategy() Strategy { return StrategyInodeDevice }
@@ -0,0 +1,60 @@
+package identity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// markerPrefix is the header line written into the first line of the
+// YAML file to carry a stable identity, e.g. "# dagu-id: <uuid>".
+const markerPrefix = "# dagu-id: "
+
+// markerResolver implements StrategyMarker. It reads the identity from
+// the file's first line, writing a new one if none is present yet.
+type markerResolver struct{}
+
+func (markerResolver) Resolve(path string) (string, error) {
+	id, err := readMarker(path)
+	if err != nil {
+		return "", err
+	}
+	if id != "" {
+		return id, nil
+	}
+	return writeMarker(path)
+}
+
+func (markerResolver) Strategy() Strategy { return StrategyMarker }
+
+func readMarker(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, markerPrefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, markerPrefix)), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+func writeMarker(path string) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	id := uuid.New().String()
+	content := fmt.Sprintf("%s%s\n%s", markerPrefix, id, existing)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", err
+	}
+	return id, nil
+}
@@ -0,0 +1,12 @@
+package identity
+
+import "path/filepath"
+
+// pathResolver implements StrategyPath.
+type pathResolver struct{}
+
+func (pathResolver) Resolve(path string) (string, error) {
+	return filepath.Base(path), nil
+}
+
+func (pathResolver) Strategy() Strategy { return StrategyPath }
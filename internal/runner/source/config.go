@@ -0,0 +1,77 @@
+package source
+
+import (
+	"context"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/yohamta/dagu/internal/runner/identity"
+)
+
+// Type selects which DAGSource implementation a Config builds.
+type Type string
+
+const (
+	TypeFilesystem Type = "filesystem"
+	TypeHTTP       Type = "http"
+	TypeGit        Type = "git"
+	TypeS3         Type = "s3"
+)
+
+// Config describes one entry of admin.Config.Sources. Exactly the field
+// matching Type is read; the others are ignored.
+type Config struct {
+	Type       Type
+	Filesystem FilesystemConfig
+	HTTP       HTTPConfig
+	Git        GitConfig
+	S3         S3Config
+}
+
+// FilesystemConfig configures a FilesystemSource. Admin.DAGs is sugar for
+// a single Config{Type: TypeFilesystem} entry with this Dir set to it.
+type FilesystemConfig struct {
+	Dir      string
+	Identity identity.Strategy
+	// IncludeGlobs and ExcludeGlobs mirror admin.Config.DAGIncludeGlobs
+	// and DAGExcludeGlobs; see FilesystemSource for matching semantics.
+	IncludeGlobs []string
+	ExcludeGlobs []string
+}
+
+// New builds the DAGSource described by cfg.
+func New(cfg Config) (DAGSource, error) {
+	switch cfg.Type {
+	case TypeFilesystem, "":
+		fs := NewFilesystemSource(cfg.Filesystem.Dir, identity.New(cfg.Filesystem.Identity))
+		fs.IncludeGlobs = cfg.Filesystem.IncludeGlobs
+		fs.ExcludeGlobs = cfg.Filesystem.ExcludeGlobs
+		return fs, nil
+	case TypeHTTP:
+		return NewHTTPSource(cfg.HTTP), nil
+	case TypeGit:
+		return NewGitSource(cfg.Git), nil
+	case TypeS3:
+		awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("source: failed to load aws config: %w", err)
+		}
+		return NewS3Source(s3.NewFromConfig(awsCfg), cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("source: unknown source type %q", cfg.Type)
+	}
+}
+
+// NewMulti builds a MultiSource from cfgs, in precedence order.
+func NewMulti(cfgs []Config) (DAGSource, error) {
+	sources := make([]DAGSource, 0, len(cfgs))
+	for i, cfg := range cfgs {
+		s, err := New(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("source: failed to build source %d: %w", i, err)
+		}
+		sources = append(sources, s)
+	}
+	return &MultiSource{Sources: sources}, nil
+}
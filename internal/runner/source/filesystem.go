@@ -0,0 +1,339 @@
+package source
+
+import (
+	"context"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/fsnotify/fsnotify"
+	"github.com/yohamta/dagu/internal/dag"
+	"github.com/yohamta/dagu/internal/runner/filenotify"
+	"github.com/yohamta/dagu/internal/runner/identity"
+	"github.com/yohamta/dagu/internal/utils"
+)
+
+// defaultRenameGracePeriod bounds how long a DAG resolved by an identity
+// other than identity.StrategyPath is kept around after its last known
+// path disappears, giving Watch a chance to see the matching Create
+// event for a rename before reporting a real removal.
+const defaultRenameGracePeriod = 2 * time.Second
+
+// FilesystemSource is the original DAGSource: a directory tree of DAG
+// YAML files, discovered recursively with filepath.WalkDir and watched
+// with fsnotify on every subdirectory. Each file is resolved to a stable
+// identity via Identity so a rename keeps the DAG's DAGRef.Name: the new
+// path is reported as a create for that same Name, and no remove follows.
+type FilesystemSource struct {
+	Dir      string
+	Identity identity.Resolver
+	// IncludeGlobs restricts discovery to files whose path relative to
+	// Dir matches at least one doublestar pattern (e.g. "prod/**/*.yaml").
+	// A nil/empty slice includes everything.
+	IncludeGlobs []string
+	// ExcludeGlobs drops files whose relative path matches any pattern
+	// (e.g. "**/_archive/**"), applied after IncludeGlobs.
+	ExcludeGlobs []string
+	// GracePeriod overrides defaultRenameGracePeriod; tests set this to
+	// something short so they don't have to sleep seconds to observe the
+	// rename-vs-remove distinction.
+	GracePeriod time.Duration
+
+	mu           sync.Mutex
+	pathIdentity map[string]string
+}
+
+var _ DAGSource = (*FilesystemSource)(nil)
+
+// NewFilesystemSource creates a FilesystemSource rooted at dir, resolving
+// identities with resolver.
+func NewFilesystemSource(dir string, resolver identity.Resolver) *FilesystemSource {
+	return &FilesystemSource{
+		Dir:          dir,
+		Identity:     resolver,
+		GracePeriod:  defaultRenameGracePeriod,
+		pathIdentity: map[string]string{},
+	}
+}
+
+func (f *FilesystemSource) List() ([]DAGRef, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var refs []DAGRef
+	err := filepath.WalkDir(f.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !utils.MatchExtension(d.Name(), dag.EXTENSIONS) {
+			return nil
+		}
+		rel, err := filepath.Rel(f.Dir, path)
+		if err != nil {
+			log.Printf("source: failed to compute relative path for %s: %s", path, err)
+			return nil
+		}
+		if !f.included(rel) {
+			return nil
+		}
+		id, err := f.Identity.Resolve(path)
+		if err != nil {
+			log.Printf("source: failed to resolve dag identity for %s: %s", path, err)
+			return nil
+		}
+		f.pathIdentity[path] = id
+		refs = append(refs, f.ref(id, rel, path))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return refs, nil
+}
+
+func (f *FilesystemSource) Load(ref DAGRef) (*dag.DAG, error) {
+	cl := dag.Loader{}
+	return cl.LoadHeadOnly(ref.Path)
+}
+
+func (f *FilesystemSource) Watch(ctx context.Context) (<-chan SourceEvent, error) {
+	watcher, err := filenotify.New(time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	addDir := func(dir string) error {
+		return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			return watcher.Add(path)
+		})
+	}
+	if err := addDir(f.Dir); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	ch := make(chan SourceEvent)
+	go func() {
+		defer close(ch)
+		defer func() { _ = watcher.Close() }()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events():
+				if !ok {
+					return
+				}
+				f.handleEvent(ctx, addDir, ch, event)
+			case err, ok := <-watcher.Errors():
+				if !ok {
+					return
+				}
+				log.Println("source: filesystem watch error:", err)
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func (f *FilesystemSource) handleEvent(ctx context.Context, addDir func(string) error, ch chan<- SourceEvent, event fsnotify.Event) {
+	path := event.Name
+	if event.Op == fsnotify.Create {
+		if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+			// A subdirectory appeared (e.g. a moved-in folder). Watch it
+			// and everything nested inside it, and report any DAGs it
+			// already contains: fsnotify fires one Create for the
+			// directory itself, not one per file underneath.
+			if err := addDir(path); err != nil {
+				log.Printf("source: failed to watch new dag subdirectory %s: %s", path, err)
+			}
+			f.emitExisting(ctx, ch, path)
+			return
+		}
+	}
+	if !utils.MatchExtension(path, dag.EXTENSIONS) {
+		if event.Op == fsnotify.Rename || event.Op == fsnotify.Remove {
+			// path no longer exists, so it can't be Stat'd to tell a
+			// vanished directory from a vanished non-DAG file. Sweep
+			// tracked files for that prefix: a moved/removed directory
+			// produces one such event for its own path, not one per
+			// nested DAG.
+			f.handleVanishedDir(ctx, ch, path)
+		}
+		return
+	}
+	rel, err := filepath.Rel(f.Dir, path)
+	if err != nil {
+		log.Printf("source: failed to compute relative path for %s: %s", path, err)
+		return
+	}
+	if !f.included(rel) {
+		return
+	}
+	switch event.Op {
+	case fsnotify.Create, fsnotify.Write:
+		id, err := f.Identity.Resolve(path)
+		if err != nil {
+			log.Printf("source: failed to resolve dag identity for %s: %s", path, err)
+			return
+		}
+		f.mu.Lock()
+		_, existed := f.pathIdentity[path]
+		f.pathIdentity[path] = id
+		f.mu.Unlock()
+		evType := EventCreate
+		if existed {
+			evType = EventUpdate
+		}
+		sendEvent(ctx, ch, SourceEvent{Type: evType, Ref: f.ref(id, rel, path)})
+	case fsnotify.Rename, fsnotify.Remove:
+		f.handleVanished(ctx, ch, path, rel)
+	}
+}
+
+// emitExisting walks a newly-discovered subdirectory and emits a Create
+// event for every DAG file already inside it, so a moved-in folder's
+// contents don't stay invisible to the scheduler until the next restart.
+func (f *FilesystemSource) emitExisting(ctx context.Context, ch chan<- SourceEvent, dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !utils.MatchExtension(d.Name(), dag.EXTENSIONS) {
+			return nil
+		}
+		rel, err := filepath.Rel(f.Dir, path)
+		if err != nil || !f.included(rel) {
+			return nil
+		}
+		id, err := f.Identity.Resolve(path)
+		if err != nil {
+			log.Printf("source: failed to resolve dag identity for %s: %s", path, err)
+			return nil
+		}
+		f.mu.Lock()
+		f.pathIdentity[path] = id
+		f.mu.Unlock()
+		sendEvent(ctx, ch, SourceEvent{Type: EventCreate, Ref: f.ref(id, rel, path)})
+		return nil
+	})
+}
+
+// handleVanishedDir reacts to a Rename or Remove event for a path that
+// is not itself a DAG file, which is what a moved or removed
+// subdirectory looks like: fsnotify reports one event for the directory,
+// not one per DAG that used to live under it. Every tracked file whose
+// path has dir as a prefix is treated as vanished too.
+func (f *FilesystemSource) handleVanishedDir(ctx context.Context, ch chan<- SourceEvent, dir string) {
+	prefix := dir + string(filepath.Separator)
+	f.mu.Lock()
+	var nested []string
+	for p := range f.pathIdentity {
+		if strings.HasPrefix(p, prefix) {
+			nested = append(nested, p)
+		}
+	}
+	f.mu.Unlock()
+	for _, p := range nested {
+		rel, err := filepath.Rel(f.Dir, p)
+		if err != nil {
+			continue
+		}
+		f.handleVanished(ctx, ch, p, rel)
+	}
+}
+
+// handleVanished reacts to a Rename or Remove event for path. Under
+// identity.StrategyPath the identity changes with the file's name, so
+// the DAG is removed immediately, matching the pre-existing behavior. Under the
+// inode/marker strategies a rename keeps the same identity, so removal
+// is deferred by the grace period to give the matching Create event a
+// chance to arrive first.
+func (f *FilesystemSource) handleVanished(ctx context.Context, ch chan<- SourceEvent, path, rel string) {
+	f.mu.Lock()
+	id, ok := f.pathIdentity[path]
+	if ok {
+		delete(f.pathIdentity, path)
+	}
+	f.mu.Unlock()
+	if !ok {
+		return
+	}
+	ref := f.ref(id, rel, path)
+	if f.Identity.Strategy() == identity.StrategyPath {
+		sendEvent(ctx, ch, SourceEvent{Type: EventRemove, Ref: ref})
+		return
+	}
+	grace := f.GracePeriod
+	if grace <= 0 {
+		grace = defaultRenameGracePeriod
+	}
+	time.AfterFunc(grace, func() {
+		f.mu.Lock()
+		for _, resolved := range f.pathIdentity {
+			if resolved == id {
+				// A Create event resolved to the same identity in the
+				// meantime, so this was a rename, not a removal.
+				f.mu.Unlock()
+				return
+			}
+		}
+		f.mu.Unlock()
+		sendEvent(ctx, ch, SourceEvent{Type: EventRemove, Ref: ref})
+	})
+}
+
+// ref builds the DAGRef for a file whose identity resolved to id and
+// whose path relative to Dir is rel. DisplayName is always the relative
+// path, so logs and the admin UI show something a human recognizes; Name
+// is that same relative path under identity.StrategyPath, or the
+// resolver's opaque id under the rename-stable strategies, where it must
+// stay fixed across a move for entryReader's dags map to track the DAG
+// through the rename.
+func (f *FilesystemSource) ref(id, rel, path string) DAGRef {
+	display := filepath.ToSlash(rel)
+	name := display
+	if f.Identity.Strategy() != identity.StrategyPath {
+		name = id
+	}
+	return DAGRef{Name: name, DisplayName: display, Path: path}
+}
+
+// included reports whether rel (a file's path relative to Dir) passes
+// IncludeGlobs and ExcludeGlobs.
+func (f *FilesystemSource) included(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if len(f.IncludeGlobs) > 0 {
+		matched := false
+		for _, g := range f.IncludeGlobs {
+			if ok, _ := doublestar.Match(g, rel); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, g := range f.ExcludeGlobs {
+		if ok, _ := doublestar.Match(g, rel); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sendEvent delivers ev on ch, giving up if ctx is canceled first so a
+// deferred send (e.g. from handleVanished's timer) never blocks forever
+// on a channel nobody is reading anymore.
+func sendEvent(ctx context.Context, ch chan<- SourceEvent, ev SourceEvent) {
+	select {
+	case ch <- ev:
+	case <-ctx.Done():
+	}
+}
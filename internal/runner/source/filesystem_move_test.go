@@ -0,0 +1,76 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFilesystemSourceDiscoversFilesInMovedInDirectory(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+
+	sub := filepath.Join(outside, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sub, "a.yaml"), []byte("steps:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewFilesystemSource(dir, fakeResolver{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := fs.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	target := filepath.Join(dir, "sub")
+	if err := os.Rename(sub, target); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := recvEvent(t, ch)
+	wantPath := filepath.Join(target, "a.yaml")
+	if ev.Type != EventCreate || ev.Ref.Path != wantPath {
+		t.Fatalf("got %+v, want create for %s", ev, wantPath)
+	}
+}
+
+func TestFilesystemSourceRemovesNestedDAGsWhenDirectoryMovedOut(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(sub, "a.yaml")
+	if err := os.WriteFile(path, []byte("steps:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewFilesystemSource(dir, fakeResolver{})
+	fs.GracePeriod = 10 * time.Millisecond
+	if _, err := fs.List(); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := fs.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	if err := os.Rename(sub, filepath.Join(t.TempDir(), "sub")); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := recvEvent(t, ch)
+	if ev.Type != EventRemove || ev.Ref.Path != path {
+		t.Fatalf("got %+v, want remove for %s", ev, path)
+	}
+}
@@ -0,0 +1,97 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yohamta/dagu/internal/runner/identity"
+)
+
+// fakeResolver identifies a file by its content, standing in for a
+// rename-stable strategy (inode/marker) without relying on real inode
+// syscalls or YAML marker injection in a unit test.
+type fakeResolver struct{}
+
+func (fakeResolver) Resolve(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (fakeResolver) Strategy() identity.Strategy { return identity.StrategyMarker }
+
+func TestFilesystemSourceRenameWithinGracePeriodSuppressesRemove(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "a.yaml")
+	if err := os.WriteFile(oldPath, []byte("steps:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewFilesystemSource(dir, fakeResolver{})
+	fs.GracePeriod = 50 * time.Millisecond
+	if _, err := fs.List(); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := fs.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	newPath := filepath.Join(dir, "b.yaml")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := recvEvent(t, ch)
+	if ev.Type != EventCreate || ev.Ref.Path != newPath {
+		t.Fatalf("got %+v, want create for %s", ev, newPath)
+	}
+
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected second event (grace period should have suppressed the remove): %+v", ev)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestFilesystemSourceRemoveFiresAfterGracePeriod(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "a.yaml")
+	if err := os.WriteFile(path, []byte("steps:\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fs := NewFilesystemSource(dir, fakeResolver{})
+	fs.GracePeriod = 50 * time.Millisecond
+	if _, err := fs.List(); err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := fs.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	start := time.Now()
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := recvEvent(t, ch)
+	if ev.Type != EventRemove || ev.Ref.Path != path {
+		t.Fatalf("got %+v, want remove for %s", ev, path)
+	}
+	if elapsed := time.Since(start); elapsed < fs.GracePeriod {
+		t.Fatalf("remove fired after %s, want at least the %s grace period", elapsed, fs.GracePeriod)
+	}
+}
@@ -0,0 +1,169 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yohamta/dagu/internal/dag"
+)
+
+// GitConfig configures a GitSource.
+type GitConfig struct {
+	// URL is the repository to clone, e.g. "git@github.com:org/dags.git".
+	URL string
+	// Ref is the branch or tag to track. Defaults to "main".
+	Ref string
+	// Dir is a local directory used to keep a working clone between
+	// polls. It is created if it does not exist.
+	Dir string
+	// PollInterval controls how often GitSource fetches and diffs
+	// against the last seen commit.
+	PollInterval time.Duration
+}
+
+// GitSource is a DAGSource backed by a git repository of DAG YAML files.
+// It keeps a full local clone in Config.Dir and periodically fetches
+// Ref, diffing against the last seen commit SHA so only the files that
+// actually changed get a new Version.
+type GitSource struct {
+	cfg GitConfig
+	dir string
+
+	mu   sync.Mutex
+	last string
+	// versions holds each file's Version: the SHA of the most recent
+	// commit sync observed it change in, not necessarily the repo's
+	// current HEAD. Tagging every file with HEAD regardless of whether
+	// it changed would make watchByPolling reload the whole DAG set on
+	// every commit, however small.
+	versions map[string]string
+}
+
+var _ DAGSource = (*GitSource)(nil)
+
+// NewGitSource creates a GitSource. A zero Ref defaults to "main" and a
+// zero PollInterval to one minute.
+func NewGitSource(cfg GitConfig) *GitSource {
+	if cfg.Ref == "" {
+		cfg.Ref = "main"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	return &GitSource{cfg: cfg, dir: cfg.Dir, versions: map[string]string{}}
+}
+
+func (s *GitSource) List() ([]DAGRef, error) {
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+	fis, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("source: failed to read git clone: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var refs []DAGRef
+	for _, fi := range fis {
+		if !strings.HasSuffix(fi.Name(), ".yaml") && !strings.HasSuffix(fi.Name(), ".yml") {
+			continue
+		}
+		refs = append(refs, DAGRef{
+			Name:        fi.Name(),
+			DisplayName: fi.Name(),
+			Path:        filepath.Join(s.dir, fi.Name()),
+			Version:     s.versions[fi.Name()],
+		})
+	}
+	return refs, nil
+}
+
+func (s *GitSource) Load(ref DAGRef) (*dag.DAG, error) {
+	cl := dag.Loader{}
+	return cl.LoadHeadOnly(ref.Path)
+}
+
+func (s *GitSource) Watch(ctx context.Context) (<-chan SourceEvent, error) {
+	return watchByPolling(ctx, s.cfg.PollInterval, s.List), nil
+}
+
+// sync clones the repository into s.dir on first use, then fetches Ref
+// and fast-forwards to it on every call after that, updating
+// s.versions for exactly the files git diff reports as changed between
+// the previous and new HEAD.
+func (s *GitSource) sync() error {
+	first := false
+	if _, err := os.Stat(filepath.Join(s.dir, ".git")); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(s.dir), 0o755); err != nil {
+			return fmt.Errorf("source: failed to create git clone dir: %w", err)
+		}
+		cmd := exec.Command("git", "clone", "--branch", s.cfg.Ref, s.cfg.URL, s.dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("source: git clone failed: %w: %s", err, out)
+		}
+		first = true
+	} else if _, err := s.run("fetch", "origin", s.cfg.Ref); err != nil {
+		return err
+	}
+
+	newSHA, err := s.run("rev-parse", "origin/"+s.cfg.Ref)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	last := s.last
+	s.mu.Unlock()
+
+	changed := map[string]bool{}
+	if !first && last != "" && last != newSHA {
+		out, err := s.run("diff", "--name-only", "--no-renames", last+".."+newSHA)
+		if err != nil {
+			return err
+		}
+		for _, name := range strings.Fields(out) {
+			changed[filepath.Base(name)] = true
+		}
+	}
+
+	if _, err := s.run("reset", "--hard", "origin/"+s.cfg.Ref); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if first || last == "" {
+		// No baseline to diff against: every file currently in the tree
+		// is new as of newSHA.
+		fis, err := os.ReadDir(s.dir)
+		if err == nil {
+			for _, fi := range fis {
+				if strings.HasSuffix(fi.Name(), ".yaml") || strings.HasSuffix(fi.Name(), ".yml") {
+					s.versions[fi.Name()] = newSHA
+				}
+			}
+		}
+	} else {
+		for name := range changed {
+			s.versions[name] = newSHA
+		}
+	}
+	s.last = newSHA
+	return nil
+}
+
+func (s *GitSource) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("source: git %s failed: %w", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
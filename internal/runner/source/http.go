@@ -0,0 +1,103 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/yohamta/dagu/internal/dag"
+)
+
+// HTTPConfig configures an HTTPSource.
+type HTTPConfig struct {
+	// IndexURL points at a JSON document listing the available DAGs, as
+	// an array of {"name": "...", "url": "..."} objects.
+	IndexURL string
+	// PollInterval controls how often the index is re-fetched.
+	PollInterval time.Duration
+}
+
+// httpIndexEntry is one element of the JSON array served at
+// HTTPConfig.IndexURL.
+type httpIndexEntry struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// HTTPSource is a DAGSource backed by a JSON index served over HTTP. It
+// polls the index on PollInterval and relies on the server's ETag header
+// to skip re-fetching a DAG's content when it has not changed.
+type HTTPSource struct {
+	cfg    HTTPConfig
+	client *http.Client
+}
+
+var _ DAGSource = (*HTTPSource)(nil)
+
+// NewHTTPSource creates an HTTPSource. A zero PollInterval defaults to
+// one minute.
+func NewHTTPSource(cfg HTTPConfig) *HTTPSource {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	return &HTTPSource{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func (s *HTTPSource) List() ([]DAGRef, error) {
+	resp, err := s.client.Get(s.cfg.IndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("source: failed to fetch http index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source: http index returned status %d", resp.StatusCode)
+	}
+	var entries []httpIndexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("source: failed to decode http index: %w", err)
+	}
+	refs := make([]DAGRef, 0, len(entries))
+	for _, e := range entries {
+		etag, err := s.etag(e.URL)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, DAGRef{Name: e.Name, DisplayName: e.Name, Path: e.URL, Version: etag})
+	}
+	return refs, nil
+}
+
+// etag issues a HEAD request for url and returns its ETag header, which
+// stands in for Version so unchanged DAGs are not reloaded on every poll.
+func (s *HTTPSource) etag(url string) (string, error) {
+	resp, err := s.client.Head(url)
+	if err != nil {
+		return "", fmt.Errorf("source: failed to fetch etag for %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+func (s *HTTPSource) Load(ref DAGRef) (*dag.DAG, error) {
+	resp, err := s.client.Get(ref.Path)
+	if err != nil {
+		return nil, fmt.Errorf("source: failed to fetch dag %s: %w", ref.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("source: dag %s returned status %d", ref.Name, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("source: failed to read dag %s: %w", ref.Name, err)
+	}
+	cl := dag.Loader{}
+	return cl.LoadData(data)
+}
+
+func (s *HTTPSource) Watch(ctx context.Context) (<-chan SourceEvent, error) {
+	return watchByPolling(ctx, s.cfg.PollInterval, s.List), nil
+}
@@ -0,0 +1,154 @@
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yohamta/dagu/internal/dag"
+)
+
+// fakeSource is a DAGSource whose List snapshot and Watch events are both
+// driven directly by the test.
+type fakeSource struct {
+	listRefs []DAGRef
+	events   chan SourceEvent
+}
+
+func newFakeSource(refs ...DAGRef) *fakeSource {
+	return &fakeSource{listRefs: refs, events: make(chan SourceEvent)}
+}
+
+func (f *fakeSource) List() ([]DAGRef, error) { return f.listRefs, nil }
+
+func (f *fakeSource) Load(ref DAGRef) (*dag.DAG, error) { return &dag.DAG{}, nil }
+
+func (f *fakeSource) Watch(ctx context.Context) (<-chan SourceEvent, error) {
+	out := make(chan SourceEvent)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev, ok := <-f.events:
+				if !ok {
+					return
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+var _ DAGSource = (*fakeSource)(nil)
+
+func TestMultiSourceListPrecedence(t *testing.T) {
+	high := newFakeSource(DAGRef{Name: "a", Path: "high/a"}, DAGRef{Name: "b", Path: "high/b"})
+	low := newFakeSource(DAGRef{Name: "a", Path: "low/a"}, DAGRef{Name: "c", Path: "low/c"})
+	m := &MultiSource{Sources: []DAGSource{high, low}}
+
+	refs, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	byName := map[string]DAGRef{}
+	for _, r := range refs {
+		byName[r.Name] = r
+	}
+	if len(byName) != 3 {
+		t.Fatalf("got %d refs, want 3: %+v", len(byName), refs)
+	}
+	if got := byName["a"].Path; got != "high/a" {
+		t.Errorf("name %q: got path %q, want %q (higher-precedence source should win)", "a", got, "high/a")
+	}
+	if got := byName["b"].Path; got != "high/b" {
+		t.Errorf("name %q: got path %q, want %q", "b", got, "high/b")
+	}
+	if got := byName["c"].Path; got != "low/c" {
+		t.Errorf("name %q: got path %q, want %q", "c", got, "low/c")
+	}
+}
+
+func TestMultiSourceWatchDropsLowerPrecedenceCreate(t *testing.T) {
+	high := newFakeSource()
+	low := newFakeSource()
+	m := &MultiSource{Sources: []DAGSource{high, low}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, err := m.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	high.events <- SourceEvent{Type: EventCreate, Ref: DAGRef{Name: "a", Path: "high/a"}}
+	if ev := recvEvent(t, out); ev.Ref.Path != "high/a" {
+		t.Fatalf("got %+v, want high/a create", ev)
+	}
+
+	// A lower-precedence source creating the same name must not override
+	// the higher-precedence source's copy.
+	low.events <- SourceEvent{Type: EventCreate, Ref: DAGRef{Name: "a", Path: "low/a"}}
+
+	select {
+	case ev := <-out:
+		t.Fatalf("expected no event for dropped lower-precedence create, got %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMultiSourceWatchFailsOverOnOwnerRemove(t *testing.T) {
+	high := newFakeSource()
+	low := newFakeSource()
+	m := &MultiSource{Sources: []DAGSource{high, low}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, err := m.Watch(ctx)
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	high.events <- SourceEvent{Type: EventCreate, Ref: DAGRef{Name: "a", Path: "high/a"}}
+	recvEvent(t, out)
+
+	low.events <- SourceEvent{Type: EventCreate, Ref: DAGRef{Name: "a", Path: "low/a"}}
+	select {
+	case ev := <-out:
+		t.Fatalf("expected no event for dropped lower-precedence create, got %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The owner removing its copy should fail over to low's ref as an
+	// Update rather than reporting the DAG gone.
+	high.events <- SourceEvent{Type: EventRemove, Ref: DAGRef{Name: "a", Path: "high/a"}}
+	ev := recvEvent(t, out)
+	if ev.Type != EventUpdate || ev.Ref.Path != "low/a" {
+		t.Fatalf("got %+v, want update to low/a", ev)
+	}
+
+	// With no source left holding the name, removal should now go
+	// through.
+	low.events <- SourceEvent{Type: EventRemove, Ref: DAGRef{Name: "a", Path: "low/a"}}
+	ev = recvEvent(t, out)
+	if ev.Type != EventRemove {
+		t.Fatalf("got %+v, want remove", ev)
+	}
+}
+
+func recvEvent(t *testing.T, ch <-chan SourceEvent) SourceEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return SourceEvent{}
+	}
+}
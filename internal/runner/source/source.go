@@ -0,0 +1,281 @@
+// Package source lets the scheduler load its DAGs from more than a
+// single shared directory. A DAGSource discovers DAG references, loads
+// their content and watches for changes; MultiSource composes several of
+// them so a team can mix a local directory with a central catalog.
+package source
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/yohamta/dagu/internal/dag"
+)
+
+// DAGRef identifies a single DAG exposed by a DAGSource.
+type DAGRef struct {
+	// Name is the stable identity of the DAG within its source, used to
+	// key it in maps and to deduplicate across sources by MultiSource.
+	// For sources with a rename-stable identity strategy (e.g.
+	// FilesystemSource under identity.StrategyInodeDevice or
+	// StrategyMarker) this is an opaque id, not something a human should
+	// be shown — use DisplayName for that.
+	Name string
+	// DisplayName is the human-readable name for this DAG, suitable for
+	// logs and UI (e.g. its path relative to the source root). Every
+	// DAGSource implementation must set it; it equals Name for sources
+	// whose identity is already human-readable.
+	DisplayName string
+	// Path is the source-specific location of the DAG content, e.g. a
+	// local file path, a URL, or an object key.
+	Path string
+	// Version is an opaque change marker (an ETag, a git SHA, an S3
+	// version id) used to detect updates without reloading unchanged
+	// DAGs. It is empty for sources that don't support one, in which
+	// case Path is used to detect changes instead.
+	Version string
+
+	source DAGSource
+}
+
+// EventType describes what changed about a DAGRef.
+type EventType int
+
+const (
+	EventCreate EventType = iota
+	EventUpdate
+	EventRemove
+)
+
+// SourceEvent is emitted by DAGSource.Watch when a DAG appears, changes
+// or disappears.
+type SourceEvent struct {
+	Type EventType
+	Ref  DAGRef
+}
+
+// DAGSource discovers and loads DAGs from one place: a local directory,
+// an HTTP index, a git repository, an S3 bucket, or a composition of
+// several of those via MultiSource.
+type DAGSource interface {
+	// List returns every DAG currently known to this source.
+	List() ([]DAGRef, error)
+	// Load fetches the full DAG for ref.
+	Load(ref DAGRef) (*dag.DAG, error)
+	// Watch streams change events until ctx is canceled.
+	Watch(ctx context.Context) (<-chan SourceEvent, error)
+}
+
+// refChanged reports whether cur is a meaningfully different version of
+// prev, preferring the opaque Version marker when either ref has one.
+func refChanged(prev, cur DAGRef) bool {
+	if prev.Version != "" || cur.Version != "" {
+		return prev.Version != cur.Version
+	}
+	return prev.Path != cur.Path
+}
+
+// watchByPolling adapts a List-only source into a Watch stream by
+// polling it on interval and diffing against the previous snapshot. It
+// is shared by the HTTP, git and S3 sources, none of which can push
+// change notifications on their own.
+func watchByPolling(ctx context.Context, interval time.Duration, list func() ([]DAGRef, error)) <-chan SourceEvent {
+	ch := make(chan SourceEvent)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		prev := map[string]DAGRef{}
+		emit := func() {
+			refs, err := list()
+			if err != nil {
+				log.Printf("source: poll failed: %s", err)
+				return
+			}
+			cur := make(map[string]DAGRef, len(refs))
+			for _, r := range refs {
+				cur[r.Name] = r
+			}
+			for name, r := range cur {
+				if p, ok := prev[name]; !ok {
+					ch <- SourceEvent{Type: EventCreate, Ref: r}
+				} else if refChanged(p, r) {
+					ch <- SourceEvent{Type: EventUpdate, Ref: r}
+				}
+			}
+			for name, r := range prev {
+				if _, ok := cur[name]; !ok {
+					ch <- SourceEvent{Type: EventRemove, Ref: r}
+				}
+			}
+			prev = cur
+		}
+		emit()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				emit()
+			}
+		}
+	}()
+	return ch
+}
+
+// MultiSource merges several DAGSources into one, deduplicating DAGs by
+// name. Sources earlier in the slice take precedence: if two sources
+// expose a DAG with the same name, the one from the earlier source wins.
+type MultiSource struct {
+	Sources []DAGSource
+}
+
+var _ DAGSource = (*MultiSource)(nil)
+
+func (m *MultiSource) List() ([]DAGRef, error) {
+	seen := map[string]bool{}
+	var all []DAGRef
+	for _, s := range m.Sources {
+		refs, err := s.List()
+		if err != nil {
+			log.Printf("source: list failed, skipping: %s", err)
+			continue
+		}
+		for _, r := range refs {
+			if seen[r.Name] {
+				continue
+			}
+			seen[r.Name] = true
+			r.source = s
+			all = append(all, r)
+		}
+	}
+	return all, nil
+}
+
+func (m *MultiSource) Load(ref DAGRef) (*dag.DAG, error) {
+	if ref.source == nil {
+		return nil, fmt.Errorf("source: ref %q has no owning source", ref.Name)
+	}
+	return ref.source.Load(ref)
+}
+
+// Watch fans in every source's event stream, applying the same
+// precedence rule as List: for a given name, the event from the
+// earliest-indexed source that currently has it wins. A later-indexed
+// source creating or updating a name a higher-precedence source already
+// owns is dropped; removing a name only takes effect once every source
+// that has it has removed it too, and falls back to the next-highest
+// remaining source's version rather than deleting the DAG outright.
+func (m *MultiSource) Watch(ctx context.Context) (<-chan SourceEvent, error) {
+	out := make(chan SourceEvent)
+	router := newNameRouter()
+	var wg sync.WaitGroup
+	for i, s := range m.Sources {
+		events, err := s.Watch(ctx)
+		if err != nil {
+			log.Printf("source: failed to watch source %d: %s", i, err)
+			continue
+		}
+		s, i := s, i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ev := range events {
+				ev.Ref.source = s
+				if resolved, ok := router.route(i, ev); ok {
+					out <- resolved
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out, nil
+}
+
+// nameRouter tracks, per DAG name, which source indices currently hold
+// it, so MultiSource.Watch can arbitrate precedence the same way List
+// does instead of letting any source's event pass straight through.
+type nameRouter struct {
+	mu    sync.Mutex
+	names map[string]*nameState
+}
+
+// nameState is the per-name bookkeeping nameRouter needs: the last ref
+// seen from each source index that currently has this name, so Watch can
+// fall back to the next-highest-precedence source's ref when the owner
+// removes it.
+type nameState struct {
+	owner int // -1 when no source currently has this name
+	refs  map[int]DAGRef
+}
+
+func newNameRouter() *nameRouter {
+	return &nameRouter{names: map[string]*nameState{}}
+}
+
+// route decides what, if anything, Watch should emit for an event ev
+// received from source index src. The returned SourceEvent is only valid
+// when ok is true.
+func (r *nameRouter) route(src int, ev SourceEvent) (SourceEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := ev.Ref.Name
+	st, ok := r.names[name]
+	if !ok {
+		st = &nameState{owner: -1, refs: map[int]DAGRef{}}
+		r.names[name] = st
+	}
+
+	switch ev.Type {
+	case EventCreate, EventUpdate:
+		st.refs[src] = ev.Ref
+		prevOwner := st.owner
+		st.owner = lowestIndex(st.refs)
+		if st.owner != src {
+			// A higher-precedence source already owns this name.
+			return SourceEvent{}, false
+		}
+		evType := EventUpdate
+		if prevOwner != src {
+			evType = EventCreate
+		}
+		return SourceEvent{Type: evType, Ref: ev.Ref}, true
+	case EventRemove:
+		wasOwner := st.owner == src
+		delete(st.refs, src)
+		st.owner = lowestIndex(st.refs)
+		if !wasOwner {
+			// A lower-precedence source's copy disappearing doesn't
+			// affect what's currently being served for this name.
+			return SourceEvent{}, false
+		}
+		if st.owner < 0 {
+			delete(r.names, name)
+			return SourceEvent{Type: EventRemove, Ref: ev.Ref}, true
+		}
+		// The owner removed the name, but another source still has it:
+		// fail over to that source's last known ref instead of
+		// reporting the DAG gone.
+		return SourceEvent{Type: EventUpdate, Ref: st.refs[st.owner]}, true
+	default:
+		return SourceEvent{}, false
+	}
+}
+
+// lowestIndex returns the smallest key in refs, or -1 if it's empty.
+func lowestIndex(refs map[int]DAGRef) int {
+	owner := -1
+	for idx := range refs {
+		if owner == -1 || idx < owner {
+			owner = idx
+		}
+	}
+	return owner
+}